@@ -0,0 +1,11 @@
+package main
+
+import (
+	"os"
+
+	"github.com/cli/cli/v2/internal/run"
+)
+
+func main() {
+	os.Exit(run.Run())
+}
@@ -0,0 +1,38 @@
+// Package classify turns an error returned from a command into a
+// human-readable message plus an actionable remediation hint, by matching
+// it against a registry of typed classifiers.
+package classify
+
+import "errors"
+
+// Classifier inspects err and, if it recognizes it, returns the message to
+// show the user and a remediation hint. ok is false when the classifier
+// doesn't recognize err, in which case message and hint are ignored.
+type Classifier interface {
+	Classify(err error) (message, hint string, ok bool)
+}
+
+// classifiers is consulted in order; the first match wins. Built-ins are
+// registered in registry.go's init(); extensions may append their own via
+// Register.
+var classifiers []Classifier
+
+// Register adds c to the end of the classifier chain, so that custom
+// classifiers added from the extension mechanism run after gh's built-ins.
+func Register(c Classifier) {
+	classifiers = append(classifiers, c)
+}
+
+// Diagnose walks err's Unwrap chain, trying every registered classifier
+// against each layer, and returns the first match. ok is false if no
+// classifier recognized any part of the chain.
+func Diagnose(err error) (message, hint string, ok bool) {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		for _, c := range classifiers {
+			if message, hint, ok := c.Classify(e); ok {
+				return message, hint, true
+			}
+		}
+	}
+	return "", "", false
+}
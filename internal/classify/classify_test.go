@@ -0,0 +1,35 @@
+package classify
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagnose_dns(t *testing.T) {
+	err := fmt.Errorf("DNS oopsie: %w", &net.DNSError{Name: "api.github.com"})
+
+	message, hint, ok := Diagnose(err)
+	assert.True(t, ok)
+	assert.Equal(t, "error connecting to api.github.com", message)
+	assert.Equal(t, "check your internet connection or https://githubstatus.com", hint)
+}
+
+func TestDiagnose_unrecognized(t *testing.T) {
+	_, _, ok := Diagnose(fmt.Errorf("the app exploded"))
+	assert.False(t, ok)
+}
+
+func TestRegister_appendsAfterBuiltins(t *testing.T) {
+	before := len(classifiers)
+	Register(stubClassifier{})
+	t.Cleanup(func() { classifiers = classifiers[:before] })
+
+	assert.Len(t, classifiers, before+1)
+}
+
+type stubClassifier struct{}
+
+func (stubClassifier) Classify(error) (string, string, bool) { return "", "", false }
@@ -0,0 +1,172 @@
+package classify
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/cli/cli/v2/api"
+	"github.com/cli/cli/v2/internal/git"
+	"github.com/cli/cli/v2/internal/proxy"
+)
+
+func init() {
+	Register(DNSClassifier{})
+	Register(TLSClassifier{})
+	Register(ProxyClassifier{})
+	Register(RateLimitClassifier{})
+	Register(SAMLEnforcementClassifier{})
+	Register(TokenScopeClassifier{})
+	Register(GitAuthClassifier{})
+}
+
+// DNSClassifier recognizes failures to resolve a host.
+type DNSClassifier struct{}
+
+func (DNSClassifier) Classify(err error) (string, string, bool) {
+	var dnsError *net.DNSError
+	if !errors.As(err, &dnsError) {
+		return "", "", false
+	}
+	return fmt.Sprintf("error connecting to %s", dnsError.Name),
+		"check your internet connection or https://githubstatus.com", true
+}
+
+// TLSClassifier recognizes certificate and handshake failures.
+type TLSClassifier struct{}
+
+func (TLSClassifier) Classify(err error) (string, string, bool) {
+	var hostnameErr x509.HostnameError
+	if errors.As(err, &hostnameErr) {
+		return fmt.Sprintf("the certificate presented does not match host %s", hostnameErr.Host),
+			"retry with --insecure, or check that your system's trust store is up to date", true
+	}
+
+	var unknownAuthority x509.UnknownAuthorityError
+	if errors.As(err, &unknownAuthority) {
+		return "the certificate presented could not be verified",
+			"retry with --insecure, or check that your system's trust store is up to date", true
+	}
+
+	var recordHeaderErr tls.RecordHeaderError
+	if errors.As(err, &recordHeaderErr) {
+		return "failed to negotiate a TLS connection",
+			"check that the server supports TLS, or that a proxy isn't intercepting the connection", true
+	}
+
+	return "", "", false
+}
+
+// ProxyClassifier recognizes a configured proxy being unreachable.
+type ProxyClassifier struct{}
+
+func (ProxyClassifier) Classify(err error) (string, string, bool) {
+	var unreachableErr *proxy.UnreachableError
+	if !errors.As(err, &unreachableErr) {
+		return "", "", false
+	}
+	return fmt.Sprintf("error connecting to proxy %s", unreachableErr.ProxyURL.Redacted()),
+		"check that the proxy is reachable, or unset HTTP(S)_PROXY/GH_HTTPS_PROXY/GH_SOCKS_PROXY", true
+}
+
+// RateLimitClassifier recognizes primary and secondary API rate limiting.
+type RateLimitClassifier struct{}
+
+func (RateLimitClassifier) Classify(err error) (string, string, bool) {
+	var httpErr api.HTTPError
+	if !errors.As(err, &httpErr) {
+		return "", "", false
+	}
+
+	if httpErr.StatusCode == 403 && httpErr.Headers.Get("Retry-After") != "" {
+		return "you have exceeded a secondary rate limit",
+			fmt.Sprintf("wait %ss before retrying", httpErr.Headers.Get("Retry-After")), true
+	}
+	if httpErr.StatusCode == 403 && httpErr.Headers.Get("X-Ratelimit-Remaining") == "0" {
+		return "API rate limit exceeded",
+			fmt.Sprintf("you have exceeded a rate limit, it resets at %s", httpErr.Headers.Get("X-Ratelimit-Reset")), true
+	}
+	return "", "", false
+}
+
+// SAMLEnforcementClassifier recognizes orgs that require SSO authorization.
+type SAMLEnforcementClassifier struct{}
+
+func (SAMLEnforcementClassifier) Classify(err error) (string, string, bool) {
+	var httpErr api.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != 403 {
+		return "", "", false
+	}
+	ssoURL := httpErr.Headers.Get("X-Github-Sso")
+	if ssoURL == "" {
+		return "", "", false
+	}
+	idx := strings.Index(ssoURL, "url=")
+	if idx == -1 {
+		return "", "", false
+	}
+	return "organization has enabled SAML SSO and this token is not authorized",
+		fmt.Sprintf("open %s in a browser to authorize it", ssoURL[idx+len("url="):]), true
+}
+
+// TokenScopeClassifier recognizes requests missing an OAuth scope.
+type TokenScopeClassifier struct{}
+
+func (TokenScopeClassifier) Classify(err error) (string, string, bool) {
+	var httpErr api.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != 403 {
+		return "", "", false
+	}
+
+	accepted := splitScopes(httpErr.Headers.Get("X-Accepted-Oauth-Scopes"))
+	if len(accepted) == 0 {
+		return "", "", false
+	}
+
+	have := map[string]bool{}
+	for _, s := range splitScopes(httpErr.Headers.Get("X-Oauth-Scopes")) {
+		have[s] = true
+	}
+	var missing []string
+	for _, s := range accepted {
+		if !have[s] {
+			missing = append(missing, s)
+		}
+	}
+	if len(missing) == 0 {
+		return "", "", false
+	}
+
+	return "your token is missing required scopes",
+		fmt.Sprintf("run `gh auth refresh -s %s` to request them", strings.Join(missing, ",")), true
+}
+
+// splitScopes parses a comma-separated OAuth scopes header value.
+func splitScopes(raw string) []string {
+	var scopes []string
+	for _, s := range strings.Split(raw, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
+
+// GitAuthClassifier recognizes git exiting non-zero because of an SSH
+// authentication failure.
+type GitAuthClassifier struct{}
+
+func (GitAuthClassifier) Classify(err error) (string, string, bool) {
+	var gitErr *git.Error
+	if !errors.As(err, &gitErr) {
+		return "", "", false
+	}
+	if !strings.Contains(gitErr.Stderr, "Permission denied (publickey)") {
+		return "", "", false
+	}
+	return "git could not authenticate with the remote over SSH",
+		"run `gh auth setup-git` to let gh manage git's credentials, or check your SSH key setup", true
+}
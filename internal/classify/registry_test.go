@@ -0,0 +1,74 @@
+package classify
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cli/cli/v2/api"
+	"github.com/cli/cli/v2/internal/git"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenScopeClassifier_missingScope(t *testing.T) {
+	httpErr := api.HTTPError{
+		StatusCode: 403,
+		Headers: http.Header{
+			"X-Accepted-Oauth-Scopes": []string{"repo, admin:org"},
+			"X-Oauth-Scopes":          []string{"repo"},
+		},
+	}
+
+	message, hint, ok := TokenScopeClassifier{}.Classify(httpErr)
+	assert.True(t, ok)
+	assert.Equal(t, "your token is missing required scopes", message)
+	assert.Contains(t, hint, "admin:org")
+	assert.NotContains(t, hint, "repo,")
+}
+
+func TestTokenScopeClassifier_ignoresNonAuthFailures(t *testing.T) {
+	// A 404 can carry the same X-Accepted-Oauth-Scopes header as a real
+	// scope failure; it must not be misreported as one.
+	httpErr := api.HTTPError{
+		StatusCode: 404,
+		Headers: http.Header{
+			"X-Accepted-Oauth-Scopes": []string{"repo"},
+		},
+	}
+
+	_, _, ok := TokenScopeClassifier{}.Classify(httpErr)
+	assert.False(t, ok)
+}
+
+func TestTokenScopeClassifier_scopesAlreadySatisfied(t *testing.T) {
+	httpErr := api.HTTPError{
+		StatusCode: 403,
+		Headers: http.Header{
+			"X-Accepted-Oauth-Scopes": []string{"repo"},
+			"X-Oauth-Scopes":          []string{"repo, admin:org"},
+		},
+	}
+
+	_, _, ok := TokenScopeClassifier{}.Classify(httpErr)
+	assert.False(t, ok)
+}
+
+func TestGitAuthClassifier_sshPermissionDenied(t *testing.T) {
+	gitErr := &git.Error{
+		Args:   []string{"fetch", "origin"},
+		Stderr: "git@github.com: Permission denied (publickey).\nfatal: Could not read from remote repository.",
+		Err:    fmt.Errorf("exit status 128"),
+	}
+
+	message, hint, ok := GitAuthClassifier{}.Classify(gitErr)
+	assert.True(t, ok)
+	assert.Equal(t, "git could not authenticate with the remote over SSH", message)
+	assert.Contains(t, hint, "gh auth setup-git")
+}
+
+func TestGitAuthClassifier_ignoresOtherFailures(t *testing.T) {
+	gitErr := &git.Error{Args: []string{"fetch", "origin"}, Stderr: "fatal: not a git repository", Err: fmt.Errorf("exit status 128")}
+
+	_, _, ok := GitAuthClassifier{}.Classify(gitErr)
+	assert.False(t, ok)
+}
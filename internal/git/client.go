@@ -0,0 +1,88 @@
+// Package git runs git as a child process on behalf of gh commands.
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/cli/cli/v2/internal/config"
+	"github.com/cli/cli/v2/internal/proxy"
+)
+
+// Client runs git as a child process, transparently applying whatever proxy
+// gh resolved for the host being talked to.
+type Client struct {
+	GitPath string
+	Config  func() (config.Config, error)
+}
+
+// Command returns an *exec.Cmd for `git <args...>` configured to use the
+// proxy gh resolved for hostname, if any.
+func (c *Client) Command(ctx context.Context, hostname string, args ...string) (*exec.Cmd, error) {
+	cfg, err := c.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	configArgs, err := proxy.GitConfigArgs(cfg, hostname)
+	if err != nil {
+		return nil, err
+	}
+	env, err := proxy.GitEnv(cfg, hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, c.gitPath(), append(configArgs, args...)...)
+	cmd.Env = append(os.Environ(), env...)
+	return cmd, nil
+}
+
+// Run executes `git <args...>` against hostname's resolved proxy settings
+// and returns its stdout. On a non-zero exit it returns an *Error carrying
+// the stderr git printed, which callers (and error classifiers) can inspect
+// without re-running the command.
+func (c *Client) Run(ctx context.Context, hostname string, args ...string) ([]byte, error) {
+	cmd, err := c.Command(ctx, hostname, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return out, &Error{Args: args, Stderr: stderr.String(), Err: err}
+	}
+	return out, nil
+}
+
+func (c *Client) gitPath() string {
+	if c.GitPath != "" {
+		return c.GitPath
+	}
+	return "git"
+}
+
+// Error wraps a failed git invocation together with the stderr it printed,
+// so callers don't have to re-derive it from the underlying *exec.ExitError
+// (which only carries stderr when the command was run via Output, and even
+// then under a different field).
+type Error struct {
+	Args   []string
+	Stderr string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if stderr := strings.TrimSpace(e.Stderr); stderr != "" {
+		return fmt.Sprintf("git %s: %s", strings.Join(e.Args, " "), stderr)
+	}
+	return fmt.Sprintf("git %s: %s", strings.Join(e.Args, " "), e.Err)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
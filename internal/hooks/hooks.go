@@ -0,0 +1,118 @@
+// Package hooks lets users drop executables under their gh config directory
+// that run automatically before and after any gh command, for policy
+// enforcement, automation, or audit logging.
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/cli/cli/v2/internal/config"
+)
+
+// Payload is the JSON document hooks receive on stdin.
+type Payload struct {
+	Command string            `json:"command"`
+	Args    []string          `json:"args"`
+	Flags   map[string]string `json:"flags"`
+	Repo    string            `json:"repo,omitempty"`
+	Host    string            `json:"host,omitempty"`
+}
+
+// PostPayload is what post-hooks receive on stdin: the same context as
+// Payload, plus the outcome of the command.
+type PostPayload struct {
+	Payload
+	ExitCode int    `json:"exit_code"`
+	Output   string `json:"output"`
+}
+
+// Dir returns the directory gh looks in for hook executables.
+func Dir() string {
+	return filepath.Join(config.ConfigDir(), "hooks")
+}
+
+// RunPre looks for a pre-<command> executable and runs it with payload on
+// stdin. A non-zero exit aborts the command; the returned error is suitable
+// for passing straight to printError.
+func RunPre(payload Payload) error {
+	path, err := lookup("pre-" + payload.Command)
+	if err != nil || path == "" {
+		return err
+	}
+
+	cmd := exec.Command(path, payload.Args...)
+	cmd.Stdin = bytes.NewReader(mustJSON(payload))
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+
+	if len(out) > 0 {
+		return fmt.Errorf("pre-%s hook rejected this command:\n%s", payload.Command, out)
+	}
+	return fmt.Errorf("pre-%s hook rejected this command: %w", payload.Command, err)
+}
+
+// RunPost looks for a post-<command> executable and runs it, passing along
+// the command's exit code and captured output. Post-hook failures are
+// logged to stderr but never change gh's own exit code.
+func RunPost(payload Payload, exitCode int, output string) {
+	path, err := lookup("post-" + payload.Command)
+	if err != nil || path == "" {
+		return
+	}
+
+	cmd := exec.Command(path, payload.Args...)
+	cmd.Stdin = bytes.NewReader(mustJSON(PostPayload{Payload: payload, ExitCode: exitCode, Output: output}))
+	if err := cmd.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: post-%s hook failed: %s\n", payload.Command, err)
+	}
+}
+
+// HasPost reports whether a post-hook is installed for payload's command,
+// so callers can skip buffering output when there's nothing to send it to.
+func HasPost(payload Payload) bool {
+	path, err := lookup("post-" + payload.Command)
+	return err == nil && path != ""
+}
+
+// HasAny reports whether a pre- or post-hook is installed for command, so
+// callers can skip resolving context (repo, host, ...) that only hooks need.
+func HasAny(command string) bool {
+	for _, prefix := range []string{"pre-", "post-"} {
+		if path, err := lookup(prefix + command); err == nil && path != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup returns the absolute path of the named hook if it exists and is
+// executable, or "" if there's no such hook.
+func lookup(name string) (string, error) {
+	path := filepath.Join(Dir(), name)
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() || info.Mode()&0o111 == 0 {
+		return "", nil
+	}
+	return path, nil
+}
+
+func mustJSON(v interface{}) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
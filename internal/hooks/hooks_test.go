@@ -0,0 +1,49 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeHook(t *testing.T, dir, name, script string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	assert.NoError(t, os.WriteFile(path, []byte(script), 0o755))
+}
+
+func TestRunPre_blocksOnNonZeroExit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts in this test")
+	}
+
+	dir := t.TempDir()
+	t.Setenv("GH_CONFIG_DIR", dir)
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "hooks"), 0o755))
+	writeHook(t, filepath.Join(dir, "hooks"), "pre-pr-create", "#!/bin/sh\necho blocked by policy >&2\nexit 1\n")
+
+	err := RunPre(Payload{Command: "pr-create"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "blocked by policy")
+}
+
+func TestRunPre_noHookIsNoop(t *testing.T) {
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+
+	assert.NoError(t, RunPre(Payload{Command: "pr-create"}))
+}
+
+func TestHasAny(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GH_CONFIG_DIR", dir)
+	assert.False(t, HasAny("pr-create"))
+
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "hooks"), 0o755))
+	assert.False(t, HasAny("pr-create"))
+
+	writeHook(t, filepath.Join(dir, "hooks"), "post-pr-create", "#!/bin/sh\n")
+	assert.True(t, HasAny("pr-create"))
+}
@@ -0,0 +1,59 @@
+package proxy
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// GitConfigArgs returns `-c` arguments that, when prepended to a git
+// invocation talking to hostname, make git route its traffic through the
+// proxy Resolve selects for hostname, resolved separately for the http and
+// https schemes since HTTP_PROXY/HTTPS_PROXY can legitimately disagree. It
+// returns nil if no proxy applies to either scheme.
+func GitConfigArgs(cfg ConfigSource, hostname string) ([]string, error) {
+	var args []string
+	for _, scheme := range []string{"https", "http"} {
+		proxyURL, err := Resolve(cfg, &url.URL{Scheme: scheme, Host: hostname})
+		if err != nil {
+			return nil, err
+		}
+		if proxyURL == nil {
+			continue
+		}
+		args = append(args, "-c", fmt.Sprintf("http.%s://%s/.proxy=%s", scheme, hostname, proxyURL.String()))
+	}
+	return args, nil
+}
+
+// GitEnv returns additional environment variables that make git (and the
+// curl library it uses for HTTP transport) honor the same proxy Resolve
+// selects for hostname, per scheme. Callers should append the result to
+// os.Environ() before passing it to exec.Cmd.Env.
+func GitEnv(cfg ConfigSource, hostname string) ([]string, error) {
+	httpsURL, err := Resolve(cfg, &url.URL{Scheme: "https", Host: hostname})
+	if err != nil {
+		return nil, err
+	}
+	httpURL, err := Resolve(cfg, &url.URL{Scheme: "http", Host: hostname})
+	if err != nil {
+		return nil, err
+	}
+
+	var env []string
+	env = append(env, proxyEnv("HTTPS_PROXY", httpsURL)...)
+	env = append(env, proxyEnv("HTTP_PROXY", httpURL)...)
+	return env, nil
+}
+
+// proxyEnv renders proxyURL as the named environment variable, substituting
+// ALL_PROXY for a SOCKS5 proxy since curl/git don't special-case
+// scheme-prefixed SOCKS5 variables.
+func proxyEnv(name string, proxyURL *url.URL) []string {
+	if proxyURL == nil {
+		return nil
+	}
+	if proxyURL.Scheme == "socks5" || proxyURL.Scheme == "socks5h" {
+		name = "ALL_PROXY"
+	}
+	return []string{name + "=" + proxyURL.String()}
+}
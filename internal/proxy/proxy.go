@@ -0,0 +1,195 @@
+// Package proxy resolves the proxy gh should use to reach a given host and
+// exposes it both as an http.RoundTripper for the API client and as
+// arguments/environment for git child processes.
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/net/proxy"
+)
+
+// ConfigSource is the subset of gh's configuration proxy resolution reads
+// from: a per-host, per-key setting lookup.
+type ConfigSource interface {
+	Get(hostname, key string) (string, error)
+}
+
+// UnreachableError is returned when a request could not be sent because the
+// resolved proxy itself refused the connection or could not be reached (as
+// opposed to the proxy working but the destination failing).
+type UnreachableError struct {
+	ProxyURL *url.URL
+	Err      error
+}
+
+func (e *UnreachableError) Error() string {
+	return fmt.Sprintf("could not reach proxy %s: %s", e.ProxyURL.Redacted(), e.Err)
+}
+
+func (e *UnreachableError) Unwrap() error { return e.Err }
+
+// Resolve returns the effective proxy URL to use for target, or nil if no
+// proxy should be used. NO_PROXY/no_proxy is honored first and bypasses
+// every other tier, since it's meant to carve out exceptions regardless of
+// how the proxy itself was configured. After that, settings are consulted
+// in order of precedence: gh's own per-host "proxy" config, its global
+// "http.proxy" config, the GH_SOCKS_PROXY/GH_HTTPS_PROXY envs, and finally the standard
+// HTTP_PROXY/HTTPS_PROXY envs. target's scheme matters for that last step:
+// httpproxy.Config picks HTTPProxy or HTTPSProxy based on it, so callers
+// must pass the scheme they'll actually be requesting with rather than
+// assuming https.
+func Resolve(cfg ConfigSource, target *url.URL) (*url.URL, error) {
+	if noProxyMatches(target) {
+		return nil, nil
+	}
+
+	hostname := target.Hostname()
+	if raw, _ := cfg.Get(hostname, "proxy"); raw != "" {
+		return url.Parse(raw)
+	}
+	if raw, _ := cfg.Get("", "http.proxy"); raw != "" {
+		return url.Parse(raw)
+	}
+
+	for _, name := range []string{"GH_SOCKS_PROXY", "GH_HTTPS_PROXY"} {
+		if raw := os.Getenv(name); raw != "" {
+			return url.Parse(raw)
+		}
+	}
+
+	envCfg := httpproxy.FromEnvironment()
+	return envCfg.ProxyFunc()(target)
+}
+
+// noProxyMatches reports whether target is covered by NO_PROXY/no_proxy,
+// independent of which tier would otherwise resolve a proxy for it. It
+// probes httpproxy.Config with dummy HTTP(S) proxies set so that a nil
+// result means NoProxy matched, not that no proxy was configured at all.
+// httpproxy also never proxies localhost or loopback addresses, so while
+// NO_PROXY is non-empty those targets are reported as matched too.
+func noProxyMatches(target *url.URL) bool {
+	noProxy := httpproxy.FromEnvironment().NoProxy
+	if noProxy == "" {
+		return false
+	}
+	probe := &httpproxy.Config{
+		HTTPProxy:  "http://noproxy-probe.invalid",
+		HTTPSProxy: "http://noproxy-probe.invalid",
+		NoProxy:    noProxy,
+	}
+	proxyURL, _ := probe.ProxyFunc()(target)
+	return proxyURL == nil
+}
+
+// RoundTripper wraps base so that every request is routed through the proxy
+// Resolve selects for its destination host, transparently supporting
+// socks5:// and socks5h:// proxy URLs in addition to plain HTTP(S) ones.
+// Transports are cached per resolved proxy so requests share connections
+// instead of dialing fresh ones every time.
+func RoundTripper(cfg ConfigSource, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &roundTripper{cfg: cfg, base: base, transports: map[string]http.RoundTripper{}}
+}
+
+type roundTripper struct {
+	cfg  ConfigSource
+	base http.RoundTripper
+
+	mu         sync.Mutex
+	transports map[string]http.RoundTripper
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	proxyURL, err := Resolve(rt.cfg, req.URL)
+	if err != nil {
+		return nil, fmt.Errorf("resolving proxy for %s: %w", req.URL.Hostname(), err)
+	}
+	if proxyURL == nil {
+		return rt.base.RoundTrip(req)
+	}
+
+	transport, err := rt.transportFor(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil && isProxyUnreachable(proxyURL, err) {
+		return nil, &UnreachableError{ProxyURL: proxyURL, Err: err}
+	}
+	return resp, err
+}
+
+func (rt *roundTripper) transportFor(proxyURL *url.URL) (http.RoundTripper, error) {
+	key := proxyURL.String()
+
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if transport, ok := rt.transports[key]; ok {
+		return transport, nil
+	}
+
+	transport, err := newTransport(rt.base, proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	rt.transports[key] = transport
+	return transport, nil
+}
+
+// newTransport builds the transport used to reach proxyURL, derived from
+// base so that TLS trust configuration and other settings wired into base
+// keep applying once a request is routed through a proxy instead of
+// silently reverting to http.Transport's defaults.
+func newTransport(base http.RoundTripper, proxyURL *url.URL) (http.RoundTripper, error) {
+	transport := &http.Transport{}
+	if baseTransport, ok := base.(*http.Transport); ok {
+		transport = baseTransport.Clone()
+	}
+
+	switch proxyURL.Scheme {
+	case "socks5", "socks5h":
+		dialer, err := proxy.FromURL(proxyURL, proxy.Direct)
+		if err != nil {
+			return nil, fmt.Errorf("configuring SOCKS5 proxy %s: %w", proxyURL.Redacted(), err)
+		}
+		transport.Proxy = nil
+		transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return dialer.Dial(network, addr)
+		}
+	default:
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	return transport, nil
+}
+
+// isProxyUnreachable reports whether err indicates the proxy itself could
+// not be reached, as opposed to a failure reaching the destination through
+// a working proxy.
+func isProxyUnreachable(proxyURL *url.URL, err error) bool {
+	if proxyURL.Scheme == "socks5" || proxyURL.Scheme == "socks5h" {
+		// golang.org/x/net/proxy's SOCKS5 dialer returns this error in two
+		// distinct shapes: a *net.OpError when it can't even open a TCP
+		// connection to the proxy itself, and a plain error from the SOCKS
+		// handshake once connected (e.g. the proxy replying that the
+		// destination is unreachable). Only the former is a proxy outage.
+		var opErr *net.OpError
+		return errors.As(err, &opErr)
+	}
+	// For plain HTTP(S) proxies, net/http only surfaces a distinct error
+	// for the CONNECT handshake with the proxy itself; other errors are
+	// destination-side and shouldn't be misreported as a proxy outage.
+	return strings.Contains(err.Error(), "proxyconnect")
+}
@@ -0,0 +1,111 @@
+package proxy
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeConfig map[string]string
+
+func (c fakeConfig) Get(hostname, key string) (string, error) {
+	return c[hostname+"/"+key], nil
+}
+
+func TestResolve_configTakesPrecedence(t *testing.T) {
+	t.Setenv("GH_HTTPS_PROXY", "https://env.example.com")
+
+	cfg := fakeConfig{"github.com/proxy": "https://config.example.com"}
+	u, err := Resolve(cfg, &url.URL{Scheme: "https", Host: "github.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://config.example.com", u.String())
+}
+
+func TestResolve_envFallback(t *testing.T) {
+	t.Setenv("GH_HTTPS_PROXY", "socks5://env.example.com:1080")
+
+	u, err := Resolve(fakeConfig{}, &url.URL{Scheme: "https", Host: "github.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, "socks5://env.example.com:1080", u.String())
+}
+
+func TestResolve_noProxyBypass(t *testing.T) {
+	t.Setenv("HTTPS_PROXY", "https://standard.example.com")
+	t.Setenv("NO_PROXY", "github.com")
+
+	u, err := Resolve(fakeConfig{}, &url.URL{Scheme: "https", Host: "github.com"})
+	assert.NoError(t, err)
+	assert.Nil(t, u)
+}
+
+func TestResolve_noProxyBypassesConfigTier(t *testing.T) {
+	t.Setenv("NO_PROXY", "github.com")
+
+	cfg := fakeConfig{"github.com/proxy": "https://config.example.com"}
+	u, err := Resolve(cfg, &url.URL{Scheme: "https", Host: "github.com"})
+	assert.NoError(t, err)
+	assert.Nil(t, u)
+}
+
+func TestResolve_noProxyBypassesGhEnvTier(t *testing.T) {
+	t.Setenv("GH_HTTPS_PROXY", "https://env.example.com")
+	t.Setenv("NO_PROXY", "github.com")
+
+	u, err := Resolve(fakeConfig{}, &url.URL{Scheme: "https", Host: "github.com"})
+	assert.NoError(t, err)
+	assert.Nil(t, u)
+}
+
+func TestResolve_schemeSelectsCorrectEnvProxy(t *testing.T) {
+	t.Setenv("HTTPS_PROXY", "https://secure.example.com")
+	t.Setenv("HTTP_PROXY", "http://plain.example.com")
+
+	httpsURL, err := Resolve(fakeConfig{}, &url.URL{Scheme: "https", Host: "github.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://secure.example.com", httpsURL.String())
+
+	httpURL, err := Resolve(fakeConfig{}, &url.URL{Scheme: "http", Host: "github.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, "http://plain.example.com", httpURL.String())
+}
+
+func TestGitConfigArgs_noProxy(t *testing.T) {
+	args, err := GitConfigArgs(fakeConfig{}, "github.com")
+	assert.NoError(t, err)
+	assert.Nil(t, args)
+}
+
+func TestNewTransport_retainsBaseSettings(t *testing.T) {
+	base := &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	proxyURL := &url.URL{Scheme: "https", Host: "proxy.example.com"}
+
+	rt, err := newTransport(base, proxyURL)
+	assert.NoError(t, err)
+
+	transport, ok := rt.(*http.Transport)
+	assert.True(t, ok)
+	assert.Equal(t, base.TLSClientConfig, transport.TLSClientConfig)
+	assert.NotNil(t, transport.Proxy)
+}
+
+func TestIsProxyUnreachable(t *testing.T) {
+	httpProxy := &url.URL{Scheme: "https", Host: "proxy.example.com"}
+	socksProxy := &url.URL{Scheme: "socks5", Host: "proxy.example.com"}
+
+	assert.True(t, isProxyUnreachable(httpProxy, errors.New("proxyconnect tcp: dial tcp: connection refused")))
+	assert.False(t, isProxyUnreachable(httpProxy, errors.New("dial tcp: connection refused")))
+
+	// A *net.OpError means the dialer couldn't open a TCP connection to the
+	// proxy itself: that's a real proxy outage.
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	assert.True(t, isProxyUnreachable(socksProxy, dialErr))
+
+	// A plain error from the SOCKS handshake means the proxy was reached
+	// fine and it's the destination that's unreachable.
+	assert.False(t, isProxyUnreachable(socksProxy, errors.New("socks connect tcp 10.0.0.1:443: host unreachable")))
+}
@@ -0,0 +1,217 @@
+// Package run is gh's shared command-execution entry point: it builds the
+// real command tree, runs any installed pre/post hooks, executes the
+// command, and maps the result to an exit code through the same error
+// classification the binary uses. cmd/gh's main and the integration test
+// harness both call Run so hook and error-classification behavior can never
+// diverge between the real binary and the tests exercising it.
+package run
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/cli/cli/v2/internal/build"
+	"github.com/cli/cli/v2/internal/classify"
+	"github.com/cli/cli/v2/internal/hooks"
+	"github.com/cli/cli/v2/pkg/cmd/factory"
+	"github.com/cli/cli/v2/pkg/cmd/root"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	ExitOK     = 0
+	ExitError  = 1
+	ExitCancel = 2
+	ExitAuth   = 4
+)
+
+// Run builds gh's real command tree and executes it against os.Args,
+// running any installed pre/post hooks and translating a failing result
+// through PrintError, exactly like the gh binary does.
+func Run() int {
+	buildVersion := build.Version
+	f := factory.New(buildVersion)
+
+	rootCmd, err := root.NewCmdRoot(f, buildVersion, build.Date)
+	if err != nil {
+		fmt.Fprintf(f.IOStreams.ErrOut, "failed to build root command: %s\n", err)
+		return ExitError
+	}
+
+	cmd, hookArgs, _ := rootCmd.Find(os.Args[1:])
+	payload := hookPayload(f, cmd, hookArgs)
+
+	if err := hooks.RunPre(payload); err != nil {
+		PrintError(f.IOStreams.ErrOut, err, cmd, isDebug())
+		return ExitError
+	}
+
+	// gh commands write through f.IOStreams, not cmd.OutOrStdout(), so that's
+	// where output needs to be tapped for the post-hook below.
+	var captured *bytes.Buffer
+	if hooks.HasPost(payload) {
+		captured = &bytes.Buffer{}
+		origOut, origErrOut := f.IOStreams.Out, f.IOStreams.ErrOut
+		f.IOStreams.Out = io.MultiWriter(origOut, captured)
+		f.IOStreams.ErrOut = io.MultiWriter(origErrOut, captured)
+		defer func() {
+			f.IOStreams.Out = origOut
+			f.IOStreams.ErrOut = origErrOut
+		}()
+	}
+
+	exitCode := ExitOK
+	if _, err := rootCmd.ExecuteC(); err != nil {
+		switch {
+		case errors.Is(err, cmdutil.ErrCancel) || cmdutil.IsUserCancellation(err):
+			exitCode = ExitCancel
+		case errors.Is(err, cmdutil.ErrSilent):
+			exitCode = ExitError
+		default:
+			PrintError(f.IOStreams.ErrOut, err, cmd, isDebug())
+			exitCode = ExitError
+		}
+	}
+
+	var output string
+	if captured != nil {
+		output = captured.String()
+	}
+	hooks.RunPost(payload, exitCode, output)
+	return exitCode
+}
+
+// hookPayload describes the resolved command invocation for hooks, e.g.
+// `gh pr create --web` becomes command "pr-create", args ["--web"]. Repo and
+// Host are best-effort and only resolved when a hook is actually installed
+// for this command: a hook can't gate on what it can't see, and the common
+// no-hooks path shouldn't pay for a git subprocess and a config load that
+// nothing will read.
+func hookPayload(f *cmdutil.Factory, cmd *cobra.Command, args []string) hooks.Payload {
+	command := strings.Join(strings.Fields(strings.TrimPrefix(cmd.CommandPath(), "gh ")), "-")
+	payload := hooks.Payload{
+		Command: command,
+		Args:    args,
+		Flags:   parseFlagArgs(cmd.Flags(), args),
+	}
+	if !hooks.HasAny(command) {
+		return payload
+	}
+	payload.Repo, _ = currentRepo()
+	payload.Host, _ = currentHost(f)
+	return payload
+}
+
+// repoFromRemoteRE extracts "owner/repo" from the tail of a git remote URL,
+// covering both the https://host/owner/repo(.git) and git@host:owner/repo(.git)
+// forms.
+var repoFromRemoteRE = regexp.MustCompile(`[/:]([^/:]+/[^/]+?)(?:\.git)?$`)
+
+// currentRepo resolves "owner/repo" from the "origin" remote of the git repo
+// in the current directory, if any.
+func currentRepo() (string, error) {
+	out, err := exec.Command("git", "remote", "get-url", "origin").Output()
+	if err != nil {
+		return "", err
+	}
+	matches := repoFromRemoteRE.FindStringSubmatch(strings.TrimSpace(string(out)))
+	if matches == nil {
+		return "", fmt.Errorf("could not parse a repo out of remote %q", out)
+	}
+	return matches[1], nil
+}
+
+// currentHost resolves the host gh is currently authenticated against,
+// honoring GH_HOST the same way the rest of gh does.
+func currentHost(f *cmdutil.Factory) (string, error) {
+	if host := os.Getenv("GH_HOST"); host != "" {
+		return host, nil
+	}
+	cfg, err := f.Config()
+	if err != nil {
+		return "", err
+	}
+	return cfg.DefaultHost()
+}
+
+// parseFlagArgs builds a best-effort flag map straight from the raw args,
+// without calling Parse on flagSet: pflag doesn't reset a flag's value
+// between Parse calls, so parsing the same args into the command's real
+// FlagSet twice (once here, once in ExecuteC) would double-accumulate
+// repeatable flags like --label or -v. It still consults flagSet's flag
+// definitions (without mutating them) to tell a bool-like flag from one
+// that takes a value, so e.g. `--web 123` doesn't mistake the positional
+// 123 for --web's value.
+func parseFlagArgs(flagSet *pflag.FlagSet, args []string) map[string]string {
+	flags := map[string]string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name := strings.TrimLeft(arg, "-")
+		if eq := strings.IndexByte(name, '='); eq != -1 {
+			flags[name[:eq]] = name[eq+1:]
+			continue
+		}
+		if flagTakesValue(flagSet, name) && i+1 < len(args) {
+			flags[name] = args[i+1]
+			i++
+			continue
+		}
+		flags[name] = "true"
+	}
+	return flags
+}
+
+// flagTakesValue reports whether name (a long flag name or single-character
+// shorthand) requires an explicit value, per flagSet's flag definitions.
+// A flag gh hasn't registered is assumed to take a value, matching pflag's
+// own behavior for flags with no NoOptDefVal.
+func flagTakesValue(flagSet *pflag.FlagSet, name string) bool {
+	if flagSet == nil {
+		return true
+	}
+	f := flagSet.Lookup(name)
+	if f == nil && len(name) == 1 {
+		f = flagSet.ShorthandLookup(name)
+	}
+	if f == nil {
+		return true
+	}
+	return f.NoOptDefVal == ""
+}
+
+func isDebug() bool {
+	return os.Getenv("GH_DEBUG") != "" || os.Getenv("DEBUG") != ""
+}
+
+// PrintError writes err to out, preferring a classified message and
+// remediation hint (see internal/classify) over the raw error text, and
+// appending cmd's usage string for flag and unknown-command errors.
+func PrintError(out io.Writer, err error, cmd *cobra.Command, debug bool) {
+	if message, hint, ok := classify.Diagnose(err); ok {
+		fmt.Fprintln(out, message)
+		if debug {
+			fmt.Fprintln(out, err)
+		}
+		fmt.Fprintln(out, hint)
+		return
+	}
+
+	fmt.Fprintln(out, err)
+
+	var flagError *cmdutil.FlagError
+	if errors.As(err, &flagError) || strings.HasPrefix(err.Error(), "unknown command ") {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, cmd.UsageString())
+	}
+}
@@ -0,0 +1,39 @@
+// Package factory builds gh's default cmdutil.Factory.
+package factory
+
+import (
+	"net/http"
+
+	"github.com/cli/cli/v2/internal/config"
+	"github.com/cli/cli/v2/internal/git"
+	"github.com/cli/cli/v2/internal/proxy"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+)
+
+// New returns a Factory wired up with gh's default dependencies: an
+// on-disk config, a proxy-aware HTTP client, and a git client that applies
+// that same proxy configuration to child processes.
+func New(appVersion string) *cmdutil.Factory {
+	f := &cmdutil.Factory{
+		AppVersion:     appVersion,
+		ExecutableName: "gh",
+		IOStreams:      iostreams.System(),
+	}
+
+	f.Config = func() (config.Config, error) {
+		return config.Load()
+	}
+
+	f.HttpClient = func() (*http.Client, error) {
+		cfg, err := f.Config()
+		if err != nil {
+			return nil, err
+		}
+		return &http.Client{Transport: proxy.RoundTripper(cfg, http.DefaultTransport)}, nil
+	}
+
+	f.GitClient = &git.Client{Config: f.Config}
+
+	return f
+}
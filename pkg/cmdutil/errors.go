@@ -0,0 +1,51 @@
+package cmdutil
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FlagError is the kind of error raised in flag processing.
+type FlagError struct {
+	Err error
+}
+
+func (fe *FlagError) Error() string {
+	return fe.Err.Error()
+}
+
+func (fe *FlagError) Unwrap() error {
+	return fe.Err
+}
+
+// FlagErrorf formats according to a format specifier and returns the
+// string as a FlagError.
+func FlagErrorf(format string, args ...interface{}) error {
+	return FlagErrorWrap(fmt.Errorf(format, args...))
+}
+
+// FlagErrorWrap wraps the specified error as a FlagError.
+func FlagErrorWrap(err error) error { return &FlagError{err} }
+
+// IsUserCancellation reports whether err represents the user interrupting
+// a command, e.g. by pressing Ctrl-C at a prompt.
+func IsUserCancellation(err error) bool {
+	if errors.Is(err, ErrCancel) {
+		return true
+	}
+	var pe *CancelError
+	return errors.As(err, &pe)
+}
+
+// ErrCancel signals that the user canceled the command.
+var ErrCancel = errors.New("cancel")
+
+// ErrSilent signals that the command already printed all relevant
+// information and the runner should only exit non-zero.
+var ErrSilent = errors.New("silent error")
+
+// CancelError is raised when a user aborts an interactive prompt.
+type CancelError struct{}
+
+func (e *CancelError) Error() string { return "CancelError" }
+func (e *CancelError) Unwrap() error { return ErrCancel }
@@ -0,0 +1,30 @@
+package cmdutil
+
+import (
+	"net/http"
+
+	"github.com/cli/cli/v2/internal/config"
+	"github.com/cli/cli/v2/internal/git"
+	"github.com/cli/cli/v2/pkg/iostreams"
+)
+
+// Factory bundles together the dependencies shared by gh's commands.
+type Factory struct {
+	AppVersion     string
+	ExecutableName string
+
+	IOStreams *iostreams.IOStreams
+
+	// HttpClient returns a client configured with gh's proxy settings
+	// already applied. The client built by factory.New adds no
+	// authentication to requests.
+	HttpClient func() (*http.Client, error)
+
+	// GitClient runs git as a child process, configured with the same
+	// proxy settings HttpClient uses.
+	GitClient *git.Client
+
+	// Config returns the resolved gh configuration, used to read
+	// host-scoped settings such as the proxy to use for a given host.
+	Config func() (config.Config, error)
+}
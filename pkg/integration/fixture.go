@@ -0,0 +1,61 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"gopkg.in/yaml.v3"
+)
+
+// testdataDir is this package's own testdata directory, resolved once from
+// the source file's location rather than the process's current directory:
+// Harness chdirs the test into a scratch checkout, so a path relative to cwd
+// wouldn't reliably find fixtures shipped alongside this package.
+var testdataDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "testdata")
+}()
+
+// FixtureResponse describes one canned API response to serve during a test.
+type FixtureResponse struct {
+	Method string `yaml:"method"`
+	Path   string `yaml:"path"`
+	Status int    `yaml:"status"`
+	Body   string `yaml:"body"`
+}
+
+// Handler returns an http.HandlerFunc that serves this response.
+func (r FixtureResponse) Handler() http.HandlerFunc {
+	status := r.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(r.Body))
+	}
+}
+
+// LoadFixture reads testdata/<name>.yml and returns the API responses it
+// declares. Fixture files are a list of FixtureResponse entries, e.g.:
+//
+//	- method: POST
+//	  path: /repos/owner/repo/forks
+//	  body: |
+//	    {"name": "repo", "owner": {"login": "someone"}}
+func LoadFixture(name string) ([]FixtureResponse, error) {
+	path := filepath.Join(testdataDir, name+".yml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading fixture %q: %w", name, err)
+	}
+
+	var responses []FixtureResponse
+	if err := yaml.Unmarshal(data, &responses); err != nil {
+		return nil, fmt.Errorf("parsing fixture %q: %w", name, err)
+	}
+	return responses, nil
+}
@@ -0,0 +1,176 @@
+// Package integration provides a reusable harness for exercising gh
+// end-to-end: an in-process run of gh's real entry point against a mock API
+// server and a real git repository, driven through a PTY so interactive prompts can be
+// answered just like a user would answer them.
+package integration
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"regexp"
+	"testing"
+	"time"
+
+	expect "github.com/Netflix/go-expect"
+	"github.com/cli/cli/v2/internal/run"
+	"github.com/cli/safeexec"
+	"github.com/hinshun/vt10x"
+	"github.com/stretchr/testify/assert"
+)
+
+// Harness bundles the scaffolding needed to run a gh subcommand against a
+// mock API and a scratch git repository, and assert on its output.
+type Harness struct {
+	t   *testing.T
+	dir string
+
+	mux    *http.ServeMux
+	server *httptest.Server
+
+	gitExe  string
+	repoDir string
+
+	expectations []promptExpectation
+}
+
+type promptExpectation struct {
+	re    *regexp.Regexp
+	reply string
+}
+
+// NewHarness sets up a temp directory and a mock API server, and chdirs the
+// test into the temp directory for the duration of t. The scratch git
+// checkout is created by WithFixture.
+func NewHarness(t *testing.T) *Harness {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.NoError(t, os.Chdir(tempDir))
+	t.Cleanup(func() { _ = os.Chdir(oldWd) })
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	os.Setenv("HTTP_PROXY", server.URL)
+	os.Setenv("GH_CONFIG_DIR", tempDir)
+	os.Setenv("GH_NO_UPDATE_NOTIFIER", "1")
+	os.Setenv("GH_INTEGRATION_TEST", "1")
+	os.Setenv("NO_COLOR", "1")
+	os.Setenv("CLICOLOR", "0")
+	t.Cleanup(func() {
+		for _, key := range []string{"HTTP_PROXY", "GH_CONFIG_DIR", "GH_NO_UPDATE_NOTIFIER", "GH_INTEGRATION_TEST", "NO_COLOR", "CLICOLOR"} {
+			os.Unsetenv(key)
+		}
+	})
+
+	gitExe, err := safeexec.LookPath("git")
+	assert.NoError(t, err)
+
+	h := &Harness{t: t, dir: tempDir, mux: mux, server: server, gitExe: gitExe}
+	h.writeHosts("github.localhost", "monalisa", "TOKEN")
+	return h
+}
+
+func (h *Harness) writeHosts(host, user, token string) {
+	hosts := fmt.Sprintf("%s:\n  user: %s\n  oauth_token: %s\n  git_protocol: https\n", host, user, token)
+	assert.NoError(h.t, os.WriteFile("hosts.yml", []byte(hosts), 0o600))
+}
+
+// WithFixture sets up a local git checkout for owner/repo, proxied through
+// the harness's mock server, and loads any canned API responses declared in
+// the named fixture (see LoadFixture).
+func (h *Harness) WithFixture(owner, repo, fixture string) *Harness {
+	h.t.Helper()
+
+	cmd := exec.Command(h.gitExe, "init", "--quiet", "repo")
+	assert.NoError(h.t, cmd.Run())
+	h.repoDir = "repo"
+	assert.NoError(h.t, os.Chdir(h.repoDir))
+
+	url := fmt.Sprintf("http://github.localhost/%s/%s.git", owner, repo)
+	assert.NoError(h.t, exec.Command(h.gitExe, "remote", "add", "origin", url).Run())
+	assert.NoError(h.t, exec.Command(h.gitExe, "config", "--add", "remote.origin.gh-resolved", "base").Run())
+	assert.NoError(h.t, exec.Command(h.gitExe, "config", "http.http://github.localhost.proxy", h.server.URL).Run())
+
+	if fixture != "" {
+		responses, err := LoadFixture(fixture)
+		assert.NoError(h.t, err)
+		for _, r := range responses {
+			h.ExpectAPI(r.Method, r.Path, r.Handler())
+		}
+	}
+
+	return h
+}
+
+// ExpectAPI registers handlerFn to serve requests matching method and path
+// against the harness's mock API server.
+func (h *Harness) ExpectAPI(method, path string, handlerFn http.HandlerFunc) *Harness {
+	h.mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		handlerFn(w, r)
+	})
+	return h
+}
+
+// ExpectPrompt queues a reply to send the first time the console output
+// matches re. Expectations are consumed in the order they were added.
+func (h *Harness) ExpectPrompt(re *regexp.Regexp, reply string) *Harness {
+	h.expectations = append(h.expectations, promptExpectation{re: re, reply: reply})
+	return h
+}
+
+// RunGH invokes `gh` with args inside the harness, feeding queued prompt
+// replies as they're matched, and returns the command's combined output
+// (with ANSI escapes stripped) and its exit code. It runs the same
+// internal/run.Run entry point as the gh binary, so hooks and error
+// classification behave identically to a real invocation.
+func (h *Harness) RunGH(args ...string) (string, int) {
+	h.t.Helper()
+
+	buf := &bytes.Buffer{}
+	console, _, err := vt10x.NewVT10XConsole(expect.WithStdout(buf), expect.WithDefaultTimeout(5*time.Second))
+	assert.NoError(h.t, err)
+	h.t.Cleanup(func() { console.Close() })
+
+	origArgs, origIn, origOut, origErr := os.Args, os.Stdin, os.Stdout, os.Stderr
+	h.t.Cleanup(func() {
+		os.Args, os.Stdin, os.Stdout, os.Stderr = origArgs, origIn, origOut, origErr
+	})
+	os.Stdin, os.Stdout, os.Stderr = console.Tty(), console.Tty(), console.Tty()
+	os.Args = append([]string{"gh"}, args...)
+
+	donec := make(chan struct{})
+	go func() {
+		defer close(donec)
+		for _, exp := range h.expectations {
+			if _, err := console.Expect(expect.Regexp(exp.re)); err != nil {
+				return
+			}
+			_, _ = console.SendLine(exp.reply)
+		}
+		_, _ = console.ExpectString("FINISHED")
+	}()
+
+	exitCode := run.Run()
+	<-donec
+
+	out := stripANSI(buf.String())
+	return out, exitCode
+}
+
+var ansiRE = regexp.MustCompile("[\u001B\u009B][[\\]()#;?]*(?:(?:(?:[a-zA-Z\\d]*(?:;[a-zA-Z\\d]*)*)?\u0007)|(?:(?:\\d{1,4}(?:;\\d{0,4})*)?[\\dA-PRZcf-ntqry=><~]))")
+
+func stripANSI(s string) string {
+	return ansiRE.ReplaceAllString(s, "")
+}
@@ -0,0 +1,20 @@
+package integration
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/cli/cli/v2/internal/run"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHarness_RepoFork(t *testing.T) {
+	h := NewHarness(t).
+		WithFixture("owner", "repo", "repo_fork").
+		ExpectPrompt(regexp.MustCompile("Would you like to add a remote for the fork?"), "Y")
+
+	out, exitCode := h.RunGH("repo", "fork")
+	assert.Equal(t, run.ExitOK, exitCode)
+	assert.Regexp(t, "✓ Created fork someone/repo", out)
+	assert.Regexp(t, "✓ Added remote origin", out)
+}